@@ -0,0 +1,71 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command listkeys prints the fingerprint of every key in the running
+// deployment's configured signing key set, so an operator can confirm
+// which physical key a kid corresponds to before removing it from the
+// configuration during a rotation (see signer.KeySet.Fingerprints).
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/google/exposure-notifications-verification-server/pkg/config"
+
+	kms "github.com/google/exposure-notifications-server/pkg/keys"
+)
+
+func main() {
+	if err := realMain(context.Background()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func realMain(ctx context.Context) error {
+	cfg, err := config.New(ctx)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	// The concrete KeyManager backend (GCP KMS, AWS KMS, Vault, a local
+	// file - see the pkg/signer package doc) is selected by the
+	// deployment's environment and constructed outside this trimmed tree;
+	// kms.NewManager is this server's existing seam for that.
+	manager, err := kms.NewManager(ctx)
+	if err != nil {
+		return fmt.Errorf("initializing key manager: %w", err)
+	}
+
+	keys, err := cfg.KeySet(manager)
+	if err != nil {
+		return fmt.Errorf("building key set: %w", err)
+	}
+
+	fingerprints, err := keys.Fingerprints(ctx)
+	if err != nil {
+		return fmt.Errorf("listing keys: %w", err)
+	}
+
+	for _, fp := range fingerprints {
+		marker := ""
+		if fp.Primary {
+			marker = " (primary)"
+		}
+		fmt.Printf("%s\t%s\t%s%s\n", fp.KID, fp.KeyID, fp.Fingerprint, marker)
+	}
+	return nil
+}
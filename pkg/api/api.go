@@ -0,0 +1,62 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package api defines the request/response payloads for the verification
+// server's public HTTP API.
+package api
+
+import "fmt"
+
+// VerifyCodeRequest is the request schema for exchanging a short-lived
+// verification code for a long-lived verification token.
+type VerifyCodeRequest struct {
+	VerificationCode string `json:"code"`
+
+	// ClientKey optionally binds the issued token to a client-held key (see
+	// pkg/dpop). When set and the server has DPoP enabled, the verification
+	// token carries a `cnf.jkt` claim derived from this key, and redeeming it
+	// for a certificate requires a DPoP proof signed by the matching private
+	// key. A key whose thumbprint cannot be computed is ignored and the token
+	// is issued without `cnf`. Omitted clients get a token with no `cnf`
+	// claim, exactly as before this field existed.
+	ClientKey *ClientKey `json:"clientKey,omitempty"`
+}
+
+// ClientKey is the public half of the ephemeral keypair a mobile client
+// generates for proof-of-possession, in the subset of RFC 7517 JWK members
+// needed to compute its thumbprint (see pkg/dpop.JWK, which mirrors this
+// shape).
+type ClientKey struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// VerifyCodeResponse is the response schema for VerifyCodeRequest.
+type VerifyCodeResponse struct {
+	TestType          string `json:"testtype"`
+	TestDate          string `json:"symptomDate"`
+	VerificationToken string `json:"token"`
+}
+
+// ErrorResponse is the JSON body returned for failed API calls.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Error builds an ErrorResponse with a formatted message.
+func Error(format string, args ...interface{}) ErrorResponse {
+	return ErrorResponse{Error: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package config loads the verification server's environment-based
+// configuration. This file only defines the fields pkg/signer,
+// pkg/controller/verifyapi and pkg/controller/jwks need; the rest of
+// Config (realm, database connection, API key settings, etc.) lives
+// elsewhere in the full server and isn't part of this trimmed tree.
+package config
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/exposure-notifications-verification-server/pkg/signer"
+
+	"github.com/sethvargo/go-envconfig"
+)
+
+// Config is the verification server's environment-derived configuration.
+type Config struct {
+	// TokenIssuer is embedded as the `iss` and `aud` claims of issued
+	// verification tokens, and as the discovery document's `issuer`.
+	TokenIssuer string `env:"TOKEN_ISSUER, required"`
+
+	// VerificationTokenDuration is how long an issued verification token
+	// remains redeemable before it expires.
+	VerificationTokenDuration time.Duration `env:"VERIFICATION_TOKEN_DURATION, default=24h"`
+
+	// SigningKeys is this deployment's rotatable signing key set, turned
+	// into a signer.KeySet via KeySet. See signer.KeyConfig for the
+	// per-key environment variables (SIGNING_KEY_0_KEY_ID, _KID, _ALG,
+	// _PRIMARY, and so on for each configured index).
+	SigningKeys []signer.KeyConfig `env:", prefix=SIGNING_KEY"`
+
+	// DPoPEnabled gates embedding a cnf.jkt claim on issued tokens (see
+	// pkg/dpop).
+	DPoPEnabled bool `env:"DPOP_ENABLED, default=false"`
+}
+
+// New loads a Config from the process environment.
+func New(ctx context.Context) (*Config, error) {
+	var cfg Config
+	if err := envconfig.Process(ctx, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// KeySet builds this deployment's signer.KeySet from SigningKeys. manager
+// resolves each configured key to a crypto.Signer; its concrete backend
+// (a KMS, Vault, or local-file implementation) is selected elsewhere in
+// the full server and is not part of this trimmed tree.
+func (c *Config) KeySet(manager signer.KeyManager) (*signer.KeySet, error) {
+	return signer.KeySetFromConfig(manager, c.SigningKeys)
+}
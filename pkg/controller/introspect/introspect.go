@@ -0,0 +1,91 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package introspect implements an OAuth2 RFC 7662-style token
+// introspection endpoint, so the key server (or an operator) can check
+// whether a verification token is still good before relying on it, without
+// needing to decode and validate the JWT itself.
+package introspect
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/exposure-notifications-verification-server/pkg/api"
+	"github.com/google/exposure-notifications-verification-server/pkg/controller"
+	"github.com/google/exposure-notifications-verification-server/pkg/logging"
+	"github.com/google/exposure-notifications-verification-server/pkg/tokenstate"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// request mirrors RFC 7662 section 2.1: the token to introspect, identified
+// here by its JTI rather than the signed JWT, since the caller (the key
+// server) already validated the JWT's signature and is asking about
+// revocation/usage state, not authenticity.
+type request struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// response is a trimmed RFC 7662 section 2.2 response: only the fields this
+// deployment can meaningfully answer.
+type response struct {
+	Active   bool   `json:"active"`
+	Exp      int64  `json:"exp,omitempty"`
+	TestType string `json:"test_type,omitempty"`
+	TestDate string `json:"test_date,omitempty"`
+}
+
+// Introspect is a controller for the /oauth/introspect endpoint.
+type Introspect struct {
+	store  tokenstate.Store
+	logger *zap.SugaredLogger
+}
+
+// New creates a new Introspect controller backed by store.
+func New(ctx context.Context, store tokenstate.Store) controller.Controller {
+	return &Introspect{store, logging.FromContext(ctx)}
+}
+
+func (in *Introspect) Execute(c *gin.Context) {
+	var req request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, api.Error("invalid request: %v", err))
+		return
+	}
+
+	record, err := in.store.Get(c.Request.Context(), req.Token)
+	if errors.Is(err, tokenstate.ErrNotFound) {
+		// RFC 7662: an unrecognized token is reported as simply inactive,
+		// not an error.
+		c.JSON(http.StatusOK, response{Active: false})
+		return
+	}
+	if err != nil {
+		in.logger.Errorf("failed to look up token state: %v", err)
+		c.JSON(http.StatusInternalServerError, api.Error("internal server error"))
+		return
+	}
+
+	now := time.Now().UTC()
+	c.JSON(http.StatusOK, response{
+		Active:   record.Active(now),
+		Exp:      record.ExpiresAt.Unix(),
+		TestType: record.TestType,
+		TestDate: record.TestDate,
+	})
+}
@@ -0,0 +1,84 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwks
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/exposure-notifications-verification-server/pkg/config"
+	"github.com/google/exposure-notifications-verification-server/pkg/controller"
+	"github.com/google/exposure-notifications-verification-server/pkg/logging"
+	"github.com/google/exposure-notifications-verification-server/pkg/signer"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// discoveryDocument is a trimmed OIDC-style discovery document. It only
+// advertises the fields a JWT verifier needs to find and interpret our JWKS;
+// it is not a full OpenID Provider Metadata document.
+type discoveryDocument struct {
+	Issuer                           string   `json:"issuer"`
+	JWKSURI                          string   `json:"jwks_uri"`
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+}
+
+// Discovery is a controller that serves the discovery document pointing at
+// the JWKS endpoint.
+type Discovery struct {
+	config  *config.Config
+	keys    *signer.KeySet
+	jwksURI string
+	logger  *zap.SugaredLogger
+}
+
+// NewDiscovery creates a new Discovery controller. jwksURI is the absolute
+// URL at which the JWKS controller (see New) is mounted, e.g.
+// "https://verify.example.com/.well-known/jwks.json".
+func NewDiscovery(ctx context.Context, config *config.Config, keys *signer.KeySet, jwksURI string) controller.Controller {
+	return &Discovery{config, keys, jwksURI, logging.FromContext(ctx)}
+}
+
+func (d *Discovery) Execute(c *gin.Context) {
+	c.Header("Cache-Control", cacheControl)
+	c.JSON(http.StatusOK, discoveryDocument{
+		Issuer:                           d.config.TokenIssuer,
+		JWKSURI:                          d.jwksURI,
+		IDTokenSigningAlgValuesSupported: d.signingAlgs(),
+	})
+}
+
+// signingAlgs returns the distinct algorithms this issuer can actually sign
+// with, in key set order (see signer.KeySet.Keys). A key set may carry a key whose algorithm is
+// only fit for JWKS publication (see signer.Algorithm.JWTSigningMethod);
+// such a key is never primary, so this server can never produce a token in
+// that alg, and advertising it here would mislead a verifier into expecting
+// tokens it will never see.
+func (d *Discovery) signingAlgs() []string {
+	seen := map[signer.Algorithm]bool{}
+	var algs []string
+	for _, key := range d.keys.Keys() {
+		if seen[key.Algorithm] {
+			continue
+		}
+		if _, err := key.Algorithm.JWTSigningMethod(); err != nil {
+			continue
+		}
+		seen[key.Algorithm] = true
+		algs = append(algs, string(key.Algorithm))
+	}
+	return algs
+}
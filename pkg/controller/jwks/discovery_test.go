@@ -0,0 +1,86 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/exposure-notifications-verification-server/pkg/config"
+	"github.com/google/exposure-notifications-verification-server/pkg/signer"
+)
+
+func TestDiscovery_SigningAlgs_ExcludesUnsignableAlgorithm(t *testing.T) {
+	manager := newFakeManager()
+	if err := manager.addECDSA("key-es256"); err != nil {
+		t.Fatalf("addECDSA: %v", err)
+	}
+	if err := manager.addEd25519("key-eddsa"); err != nil {
+		t.Fatalf("addEd25519: %v", err)
+	}
+
+	// key-eddsa is a perfectly valid JWKS entry, but this issuer can never
+	// sign a token with it (see signer.Algorithm.JWTSigningMethod), so it
+	// must not appear in id_token_signing_alg_values_supported.
+	keys, err := signer.NewKeySet(manager,
+		signer.Key{KeyID: "key-es256", KID: "es256", Algorithm: signer.AlgorithmES256},
+		signer.Key{KeyID: "key-eddsa", KID: "eddsa", Algorithm: signer.AlgorithmEdDSA},
+	)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+
+	d := &Discovery{config: &config.Config{TokenIssuer: "https://verify.example.com"}, keys: keys, jwksURI: "https://verify.example.com/.well-known/jwks.json"}
+	got := d.signingAlgs()
+	want := []string{string(signer.AlgorithmES256)}
+	if len(got) != len(want) || got[0] != want[0] {
+		t.Errorf("signingAlgs() = %v, want %v", got, want)
+	}
+}
+
+func TestDiscovery_Execute(t *testing.T) {
+	manager := newFakeManager()
+	if err := manager.addECDSA("key-es256"); err != nil {
+		t.Fatalf("addECDSA: %v", err)
+	}
+	keys, err := signer.NewKeySet(manager,
+		signer.Key{KeyID: "key-es256", KID: "es256", Algorithm: signer.AlgorithmES256},
+	)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+
+	ctrl := NewDiscovery(context.Background(), &config.Config{TokenIssuer: "https://verify.example.com"}, keys, "https://verify.example.com/.well-known/jwks.json")
+	c, w := newTestContext()
+	ctrl.Execute(c)
+
+	if w.Code != 200 {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var doc discoveryDocument
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if doc.Issuer != "https://verify.example.com" {
+		t.Errorf("Issuer = %q, want %q", doc.Issuer, "https://verify.example.com")
+	}
+	if doc.JWKSURI != "https://verify.example.com/.well-known/jwks.json" {
+		t.Errorf("JWKSURI = %q, want %q", doc.JWKSURI, "https://verify.example.com/.well-known/jwks.json")
+	}
+	if want := []string{string(signer.AlgorithmES256)}; len(doc.IDTokenSigningAlgValuesSupported) != 1 || doc.IDTokenSigningAlgValuesSupported[0] != want[0] {
+		t.Errorf("IDTokenSigningAlgValuesSupported = %v, want %v", doc.IDTokenSigningAlgValuesSupported, want)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jwks publishes the public half of the verification token signing
+// key(s) as a standard RFC 7517 JSON Web Key Set, and an OIDC-style discovery
+// document that points at it. The key server (or any other relying party)
+// uses these endpoints to validate JWTs issued by verifyapi without needing
+// any out-of-band key distribution.
+package jwks
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/google/exposure-notifications-verification-server/pkg/api"
+	"github.com/google/exposure-notifications-verification-server/pkg/controller"
+	"github.com/google/exposure-notifications-verification-server/pkg/logging"
+	"github.com/google/exposure-notifications-verification-server/pkg/signer"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// cacheControl is sent on both endpoints so that downstream consumers (the
+// key server, third-party health-authority backends) don't refetch the key
+// set on every request. Keys are long lived, but we keep this short enough
+// that a rotation (see the signer package) propagates promptly.
+const cacheControl = "public, max-age=300"
+
+// jwk is a single RFC 7517 JSON Web Key. It is shaped to describe whichever
+// of an EC (ES256), RSA (RS256), or OKP/Ed25519 (EdDSA) public key a given
+// signing key holds; unused fields are omitted.
+type jwk struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+
+	// EC (kty=EC)
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+
+	// RSA (kty=RSA)
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+}
+
+// jwksDocument is the RFC 7517 JWK Set document.
+type jwksDocument struct {
+	Keys []jwk `json:"keys"`
+}
+
+// JWKS is a controller that serves the JWK Set document.
+type JWKS struct {
+	keys   *signer.KeySet
+	logger *zap.SugaredLogger
+}
+
+// New creates a new JWKS controller that serves every key in keys, primary
+// and retiring/standby alike, so that tokens signed during a key rotation
+// stay verifiable for as long as the key remains in the set.
+func New(ctx context.Context, keys *signer.KeySet) controller.Controller {
+	return &JWKS{keys, logging.FromContext(ctx)}
+}
+
+func (k *JWKS) Execute(c *gin.Context) {
+	keys := k.keys.Keys()
+	doc := jwksDocument{Keys: make([]jwk, 0, len(keys))}
+	for _, key := range keys {
+		j, err := k.toJWK(c.Request.Context(), key)
+		if err != nil {
+			k.logger.Errorf("failed to load signing key %q: %v", key.KID, err)
+			c.JSON(http.StatusInternalServerError, api.Error("internal server error - unable to load signing key"))
+			return
+		}
+		doc.Keys = append(doc.Keys, *j)
+	}
+
+	c.Header("Cache-Control", cacheControl)
+	c.JSON(http.StatusOK, doc)
+}
+
+// toJWK resolves key via the KeySet and translates it into a JWK with a
+// stable kid. The kid matches the header verifyapi.Execute sets on issued
+// JWTs, so verifiers can pick the right entry out of the set. The JWK shape
+// depends on key.Algorithm: ES256 keys must be ECDSA, RS256 keys must be
+// RSA, and EdDSA keys must be Ed25519.
+func (k *JWKS) toJWK(ctx context.Context, key signer.Key) (*jwk, error) {
+	s, err := k.keys.Signer(ctx, key.KID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get signing key: %w", err)
+	}
+
+	base := jwk{Use: "sig", Kid: key.KID, Alg: string(key.Algorithm)}
+
+	switch key.Algorithm {
+	case signer.AlgorithmRS256:
+		pub, ok := s.Public().(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("key %q is configured as RS256 but is not an RSA public key", key.KID)
+		}
+		base.Kty = "RSA"
+		base.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		base.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+
+	case signer.AlgorithmEdDSA:
+		pub, ok := s.Public().(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("key %q is configured as EdDSA but is not an Ed25519 public key", key.KID)
+		}
+		base.Kty = "OKP"
+		base.Crv = "Ed25519"
+		base.X = base64.RawURLEncoding.EncodeToString(pub)
+
+	default:
+		pub, ok := s.Public().(*ecdsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("key %q is configured as %s but is not an ECDSA public key", key.KID, key.Algorithm)
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		base.Kty = "EC"
+		base.Crv = pub.Curve.Params().Name
+		base.X = base64.RawURLEncoding.EncodeToString(pub.X.FillBytes(make([]byte, size)))
+		base.Y = base64.RawURLEncoding.EncodeToString(pub.Y.FillBytes(make([]byte, size)))
+	}
+
+	return &base, nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwks
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/exposure-notifications-verification-server/pkg/signer"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeManager is an in-memory KeyManager that can hand back a signer of
+// whatever key type a test registered under a given KeyID, so a single
+// KeySet can exercise all three JWK shapes toJWK knows how to produce.
+type fakeManager struct {
+	signers map[string]crypto.Signer
+}
+
+func newFakeManager() *fakeManager {
+	return &fakeManager{signers: map[string]crypto.Signer{}}
+}
+
+func (m *fakeManager) addECDSA(keyID string) error {
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	m.signers[keyID] = k
+	return nil
+}
+
+func (m *fakeManager) addRSA(keyID string) error {
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return err
+	}
+	m.signers[keyID] = k
+	return nil
+}
+
+func (m *fakeManager) addEd25519(keyID string) error {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	m.signers[keyID] = priv
+	return nil
+}
+
+func (m *fakeManager) NewSigner(ctx context.Context, keyID string) (crypto.Signer, error) {
+	s, ok := m.signers[keyID]
+	if !ok {
+		return nil, fmt.Errorf("no such key %q", keyID)
+	}
+	return s, nil
+}
+
+func newTestContext() (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/.well-known/jwks.json", nil)
+	return c, w
+}
+
+func TestJWKS_Execute(t *testing.T) {
+	manager := newFakeManager()
+	if err := manager.addECDSA("key-es256"); err != nil {
+		t.Fatalf("addECDSA: %v", err)
+	}
+	if err := manager.addRSA("key-rs256"); err != nil {
+		t.Fatalf("addRSA: %v", err)
+	}
+	if err := manager.addEd25519("key-eddsa"); err != nil {
+		t.Fatalf("addEd25519: %v", err)
+	}
+
+	keys, err := signer.NewKeySet(manager,
+		signer.Key{KeyID: "key-es256", KID: "es256", Algorithm: signer.AlgorithmES256},
+		signer.Key{KeyID: "key-rs256", KID: "rs256", Algorithm: signer.AlgorithmRS256},
+		signer.Key{KeyID: "key-eddsa", KID: "eddsa", Algorithm: signer.AlgorithmEdDSA},
+	)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+
+	ctrl := New(context.Background(), keys)
+	c, w := newTestContext()
+	ctrl.Execute(c)
+
+	if w.Code != 200 {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var doc jwksDocument
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(doc.Keys) != 3 {
+		t.Fatalf("len(doc.Keys) = %d, want 3", len(doc.Keys))
+	}
+
+	byKID := map[string]jwk{}
+	for _, k := range doc.Keys {
+		byKID[k.Kid] = k
+	}
+
+	if got := byKID["es256"]; got.Kty != "EC" || got.Crv == "" || got.X == "" || got.Y == "" {
+		t.Errorf("es256 jwk = %+v, want populated EC fields", got)
+	}
+	if got := byKID["rs256"]; got.Kty != "RSA" || got.N == "" || got.E == "" {
+		t.Errorf("rs256 jwk = %+v, want populated RSA fields", got)
+	}
+	if got := byKID["eddsa"]; got.Kty != "OKP" || got.Crv != "Ed25519" || got.X == "" {
+		t.Errorf("eddsa jwk = %+v, want populated OKP fields", got)
+	}
+}
+
+func TestJWKS_Execute_MismatchedKeyMaterial(t *testing.T) {
+	manager := newFakeManager()
+	// Registered as RS256 in the KeySet but the manager hands back an ECDSA
+	// signer - a misconfiguration toJWK must reject rather than publish a
+	// garbled JWK.
+	if err := manager.addECDSA("key-a"); err != nil {
+		t.Fatalf("addECDSA: %v", err)
+	}
+
+	keys, err := signer.NewKeySet(manager,
+		signer.Key{KeyID: "key-a", KID: "a", Algorithm: signer.AlgorithmRS256},
+	)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+
+	ctrl := New(context.Background(), keys)
+	c, w := newTestContext()
+	ctrl.Execute(c)
+
+	if w.Code != 500 {
+		t.Errorf("status = %d, want 500", w.Code)
+	}
+}
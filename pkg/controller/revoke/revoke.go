@@ -0,0 +1,73 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package revoke implements an admin endpoint that kills a verification
+// token before it is exchanged at the key server, e.g. in response to a
+// stolen-device or accidental-issuance report. It is meant to be mounted
+// behind the same admin authentication as the rest of the operator console;
+// this package does not itself authorize the caller.
+package revoke
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/exposure-notifications-verification-server/pkg/api"
+	"github.com/google/exposure-notifications-verification-server/pkg/controller"
+	"github.com/google/exposure-notifications-verification-server/pkg/logging"
+	"github.com/google/exposure-notifications-verification-server/pkg/tokenstate"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type request struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type response struct {
+	Revoked bool `json:"revoked"`
+}
+
+// Revoke is a controller for the /oauth/revoke endpoint.
+type Revoke struct {
+	store  tokenstate.Store
+	logger *zap.SugaredLogger
+}
+
+// New creates a new Revoke controller backed by store.
+func New(ctx context.Context, store tokenstate.Store) controller.Controller {
+	return &Revoke{store, logging.FromContext(ctx)}
+}
+
+func (rv *Revoke) Execute(c *gin.Context) {
+	var req request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, api.Error("invalid request: %v", err))
+		return
+	}
+
+	if err := rv.store.Revoke(c.Request.Context(), req.Token); err != nil {
+		if errors.Is(err, tokenstate.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("unknown token"))
+			return
+		}
+		rv.logger.Errorf("failed to revoke token: %v", err)
+		c.JSON(http.StatusInternalServerError, api.Error("internal server error"))
+		return
+	}
+
+	c.JSON(http.StatusOK, response{Revoked: true})
+}
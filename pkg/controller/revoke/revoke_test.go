@@ -0,0 +1,88 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revoke
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/exposure-notifications-verification-server/pkg/tokenstate"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestContext(t *testing.T, body interface{}) (*gin.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	raw, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/oauth/revoke", bytes.NewReader(raw))
+	c.Request.Header.Set("Content-Type", "application/json")
+	return c, w
+}
+
+func TestRevoke_Execute(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	store := tokenstate.NewMemoryStore()
+	if err := store.Insert(context.Background(), tokenstate.Record{
+		TokenID:   "jti-1",
+		ExpiresAt: now.Add(time.Hour),
+		State:     tokenstate.Active,
+	}); err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+
+	ctrl := New(context.Background(), store)
+	c, w := newTestContext(t, request{Token: "jti-1"})
+	ctrl.Execute(c)
+
+	if w.Code != 200 {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var resp response
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !resp.Revoked {
+		t.Errorf("Revoked = false, want true")
+	}
+
+	rec, err := store.Get(context.Background(), "jti-1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if rec.State != tokenstate.Revoked {
+		t.Errorf("State = %q, want %q", rec.State, tokenstate.Revoked)
+	}
+}
+
+func TestRevoke_Execute_NotFound(t *testing.T) {
+	store := tokenstate.NewMemoryStore()
+	ctrl := New(context.Background(), store)
+	c, w := newTestContext(t, request{Token: "missing"})
+	ctrl.Execute(c)
+
+	if w.Code != 404 {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+}
@@ -30,9 +30,11 @@ import (
 	"github.com/google/exposure-notifications-verification-server/pkg/config"
 	"github.com/google/exposure-notifications-verification-server/pkg/controller"
 	"github.com/google/exposure-notifications-verification-server/pkg/database"
+	"github.com/google/exposure-notifications-verification-server/pkg/dpop"
 	"github.com/google/exposure-notifications-verification-server/pkg/jwthelper"
 	"github.com/google/exposure-notifications-verification-server/pkg/logging"
 	"github.com/google/exposure-notifications-verification-server/pkg/signer"
+	"github.com/google/exposure-notifications-verification-server/pkg/tokenstate"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
@@ -44,11 +46,30 @@ type VerifyAPI struct {
 	config *config.Config
 	db     *database.Database
 	logger *zap.SugaredLogger
-	signer signer.KeyManager
+	keys   *signer.KeySet
+
+	// dpopEnabled gates embedding a cnf.jkt claim for clients that send a
+	// ClientKey (see pkg/dpop). It applies to every request this controller
+	// handles; there is no per-realm setting. Off by default (see
+	// config.Config.DPoPEnabled) so existing deployments keep issuing plain
+	// tokens.
+	dpopEnabled bool
+
+	// tokens records issued tokens for introspection/revocation (see
+	// pkg/tokenstate and pkg/controller/introspect, pkg/controller/revoke).
+	// May be nil, in which case those endpoints have nothing to report.
+	tokens tokenstate.Store
 }
 
-func New(ctx context.Context, config *config.Config, db *database.Database, signer signer.KeyManager) controller.Controller {
-	return &VerifyAPI{config, db, logging.FromContext(ctx), signer}
+func New(ctx context.Context, config *config.Config, db *database.Database, keys *signer.KeySet, dpopEnabled bool, tokens tokenstate.Store) controller.Controller {
+	return &VerifyAPI{config, db, logging.FromContext(ctx), keys, dpopEnabled, tokens}
+}
+
+// verificationClaims extends the standard JWT claims with the optional RFC
+// 7800 confirmation claim used to bind the token to a client-held key.
+type verificationClaims struct {
+	jwt.StandardClaims
+	Cnf *dpop.Confirmation `json:"cnf,omitempty"`
 }
 
 func (v *VerifyAPI) Execute(c *gin.Context) {
@@ -60,8 +81,17 @@ func (v *VerifyAPI) Execute(c *gin.Context) {
 		return
 	}
 
-	// Get the signer based on Key configuration.
-	signer, err := v.signer.NewSigner(c.Request.Context(), v.config.TokenSigningKey)
+	// Always sign with the primary key. Older keys are kept around in the
+	// KeySet purely so in-flight tokens they already signed can still be
+	// verified during a rotation's grace period.
+	primary := v.keys.Primary()
+	signingMethod, err := primary.Algorithm.JWTSigningMethod()
+	if err != nil {
+		v.logger.Errorf("unable to sign with primary key: %v", err)
+		c.JSON(http.StatusInternalServerError, api.Error("internal server error - unable to sign tokens"))
+		return
+	}
+	signer, err := v.keys.PrimarySigner(c.Request.Context())
 	if err != nil {
 		v.logger.Errorf("unable to get signing key: %v", err)
 		c.JSON(http.StatusInternalServerError, api.Error("internal server error - unable to sign tokens"))
@@ -83,15 +113,59 @@ func (v *VerifyAPI) Execute(c *gin.Context) {
 
 	subject := verificationToken.TestType + "." + verificationToken.FormatTestDate()
 	now := time.Now().UTC()
-	claims := &jwt.StandardClaims{
-		Audience:  v.config.TokenIssuer,
-		ExpiresAt: now.Add(v.config.VerificationTokenDuration).Unix(),
-		Id:        verificationToken.TokenID,
-		IssuedAt:  now.Unix(),
-		Issuer:    v.config.TokenIssuer,
-		Subject:   subject,
+	expiresAt := now.Add(v.config.VerificationTokenDuration)
+
+	if v.tokens != nil {
+		record := tokenstate.Record{
+			TokenID:   verificationToken.TokenID,
+			TestType:  verificationToken.TestType,
+			TestDate:  verificationToken.FormatTestDate(),
+			ExpiresAt: expiresAt,
+			State:     tokenstate.Active,
+		}
+		if err := v.tokens.Insert(c.Request.Context(), record); err != nil {
+			// Introspection state is a convenience, not required for the
+			// token itself to be valid - log and keep issuing it.
+			v.logger.Errorf("failed to record token state: %v", err)
+		}
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+
+	claims := &verificationClaims{
+		StandardClaims: jwt.StandardClaims{
+			Audience:  v.config.TokenIssuer,
+			ExpiresAt: expiresAt.Unix(),
+			Id:        verificationToken.TokenID,
+			IssuedAt:  now.Unix(),
+			Issuer:    v.config.TokenIssuer,
+			Subject:   subject,
+		},
+	}
+
+	if v.dpopEnabled && request.ClientKey != nil {
+		jkt, err := (dpop.JWK{
+			Kty: request.ClientKey.Kty,
+			Crv: request.ClientKey.Crv,
+			X:   request.ClientKey.X,
+			Y:   request.ClientKey.Y,
+		}).Thumbprint()
+		if err != nil {
+			v.logger.Errorf("invalid client key, issuing token without proof-of-possession binding: %v", err)
+		} else {
+			claims.Cnf = &dpop.Confirmation{JKT: jkt}
+			if err := v.db.SetVerificationTokenJKT(c.Request.Context(), verificationToken.TokenID, jkt); err != nil {
+				// Same best-effort treatment as the tokenstate.Insert call
+				// above - the JWT itself already carries the cnf claim, so
+				// a write failure here only leaves the verification_tokens
+				// row without the thumbprint, not the token invalid.
+				v.logger.Errorf("failed to persist token confirmation thumbprint: %v", err)
+			}
+		}
+	}
+
+	token := jwt.NewWithClaims(signingMethod, claims)
+	// Set kid so that verifiers can select the matching JWK from the
+	// /.well-known/jwks.json document (see pkg/controller/jwks).
+	token.Header["kid"] = primary.KID
 	signedJWT, err := jwthelper.SignJWT(token, signer)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, api.Error("error signing token, must obtain new verification code"))
@@ -103,4 +177,4 @@ func (v *VerifyAPI) Execute(c *gin.Context) {
 		TestDate:          verificationToken.FormatTestDate(),
 		VerificationToken: signedJWT,
 	})
-}
\ No newline at end of file
+}
@@ -0,0 +1,35 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package database persists the verification server's durable state in a
+// relational database via gorm. This file only defines the connection
+// wrapper itself; the realm/user/verification-code/API-key tables and
+// their methods (including VerifyCodeAndIssueToken, the VerificationToken
+// model, and the ErrVerificationCode* sentinel errors referenced from
+// pkg/controller/verifyapi) live elsewhere in the full server and aren't
+// part of this trimmed tree. See token_state.go and verification_token.go
+// for the pieces this tree does own.
+package database
+
+import "gorm.io/gorm"
+
+// Database wraps the server's database connection pool.
+type Database struct {
+	db *gorm.DB
+}
+
+// New wraps an already-open gorm connection.
+func New(db *gorm.DB) *Database {
+	return &Database{db: db}
+}
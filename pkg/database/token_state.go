@@ -0,0 +1,115 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/exposure-notifications-verification-server/pkg/tokenstate"
+
+	"gorm.io/gorm"
+)
+
+// TokenState is the persisted row backing tokenstate.Store: one per issued
+// verification token, keyed by TokenID (the JWT `jti` claim,
+// VerificationToken.TokenID). ExpiresAt is indexed so a periodic GC sweep
+// (see PurgeTokenStates) can find elapsed rows without a table scan;
+// tokenstate.MemoryStore.GC, by contrast, walks every record.
+type TokenState struct {
+	TokenID   string    `gorm:"column:token_id;primaryKey"`
+	TestType  string    `gorm:"column:test_type"`
+	TestDate  string    `gorm:"column:test_date"`
+	ExpiresAt time.Time `gorm:"column:expires_at;index"`
+	State     string    `gorm:"column:state"`
+}
+
+// TableName overrides gorm's default pluralized name so the table matches
+// the type name used throughout pkg/tokenstate's docs.
+func (TokenState) TableName() string {
+	return "token_states"
+}
+
+var _ tokenstate.Store = (*Database)(nil)
+
+// Insert implements tokenstate.Store.
+func (db *Database) Insert(ctx context.Context, r tokenstate.Record) error {
+	row := TokenState{
+		TokenID:   r.TokenID,
+		TestType:  r.TestType,
+		TestDate:  r.TestDate,
+		ExpiresAt: r.ExpiresAt,
+		State:     string(r.State),
+	}
+	if err := db.db.WithContext(ctx).Create(&row).Error; err != nil {
+		return fmt.Errorf("database: inserting token state: %w", err)
+	}
+	return nil
+}
+
+// Get implements tokenstate.Store.
+func (db *Database) Get(ctx context.Context, tokenID string) (*tokenstate.Record, error) {
+	var row TokenState
+	if err := db.db.WithContext(ctx).First(&row, "token_id = ?", tokenID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, tokenstate.ErrNotFound
+		}
+		return nil, fmt.Errorf("database: looking up token state: %w", err)
+	}
+	return &tokenstate.Record{
+		TokenID:   row.TokenID,
+		TestType:  row.TestType,
+		TestDate:  row.TestDate,
+		ExpiresAt: row.ExpiresAt,
+		State:     tokenstate.State(row.State),
+	}, nil
+}
+
+// MarkUsed implements tokenstate.Store.
+func (db *Database) MarkUsed(ctx context.Context, tokenID string) error {
+	return db.transitionTokenState(ctx, tokenID, tokenstate.Used)
+}
+
+// Revoke implements tokenstate.Store.
+func (db *Database) Revoke(ctx context.Context, tokenID string) error {
+	return db.transitionTokenState(ctx, tokenID, tokenstate.Revoked)
+}
+
+func (db *Database) transitionTokenState(ctx context.Context, tokenID string, state tokenstate.State) error {
+	result := db.db.WithContext(ctx).
+		Model(&TokenState{}).
+		Where("token_id = ?", tokenID).
+		Update("state", string(state))
+	if result.Error != nil {
+		return fmt.Errorf("database: updating token state: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return tokenstate.ErrNotFound
+	}
+	return nil
+}
+
+// PurgeTokenStates deletes every TokenState row whose ExpiresAt has
+// passed, regardless of State - the same rule tokenstate.MemoryStore.GC
+// applies in-process. It is meant to be called periodically by the
+// server's cleanup job so the table doesn't grow unbounded.
+func (db *Database) PurgeTokenStates(ctx context.Context, now time.Time) error {
+	if err := db.db.WithContext(ctx).Where("expires_at < ?", now).Delete(&TokenState{}).Error; err != nil {
+		return fmt.Errorf("database: purging token state: %w", err)
+	}
+	return nil
+}
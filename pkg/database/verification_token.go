@@ -0,0 +1,47 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrVerificationTokenNotFound is returned by SetVerificationTokenJKT when
+// no verification_tokens row exists for the given TokenID.
+var ErrVerificationTokenNotFound = errors.New("database: verification token not found")
+
+// SetVerificationTokenJKT persists the RFC 7800 `cnf.jkt` thumbprint (see
+// dpop.Confirmation) against the verification_tokens row VerifyCodeAndIssueToken
+// already created for tokenID, so an operator can learn a token is
+// DPoP-bound without decoding the JWT. /oauth/introspect (see
+// pkg/controller/introspect) does not read or report this column. The
+// VerificationToken model and VerifyCodeAndIssueToken
+// itself live elsewhere in the full server; cnf_jkt is the one column
+// pkg/dpop's proof-of-possession feature needs on that table.
+func (db *Database) SetVerificationTokenJKT(ctx context.Context, tokenID, jkt string) error {
+	result := db.db.WithContext(ctx).
+		Table("verification_tokens").
+		Where("token_id = ?", tokenID).
+		Update("cnf_jkt", jkt)
+	if result.Error != nil {
+		return fmt.Errorf("database: persisting token confirmation thumbprint: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrVerificationTokenNotFound
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dpop
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// Confirmation is the RFC 7800 `cnf` claim embedded in a verification token
+// to bind it to the client key whose thumbprint is JKT. JKT is also
+// persisted to the verification_tokens row for the same token (see
+// database.Database.SetVerificationTokenJKT), so an operator can learn a
+// token is DPoP-bound without decoding the JWT itself; the introspection
+// endpoint (see pkg/controller/introspect) does not report it.
+type Confirmation struct {
+	JKT string `json:"jkt"`
+}
+
+// TokenHash returns the base64url (no padding) SHA-256 hash of token, used
+// as a DPoP proof's `ath` claim to additionally bind the proof to the
+// specific bearer token it accompanies.
+func TokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dpop binds a verification token to a client-held key, so that a
+// leaked long-term token cannot be redeemed by a different device. The
+// mobile client generates an ephemeral keypair, sends its public JWK with
+// the verification code, and the token's `cnf.jkt` claim (RFC 7800) records
+// the key's thumbprint. Certificate issuance later requires the client to
+// present a DPoP proof JWT (draft-ietf-oauth-dpop) signed by that key.
+package dpop
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// JWK is the subset of RFC 7517 JWK members needed to compute an RFC 7638
+// thumbprint and to verify a proof signature, for the key types mobile
+// clients are expected to send: EC (P-256) and OKP (Ed25519).
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// Thumbprint computes the RFC 7638 SHA-256 thumbprint of the JWK: the
+// base64url (no padding) encoding of the hash of the JSON object containing
+// only the key's required members, lexicographically ordered.
+func (k JWK) Thumbprint() (string, error) {
+	var members map[string]string
+	switch k.Kty {
+	case "EC":
+		members = map[string]string{"crv": k.Crv, "kty": k.Kty, "x": k.X, "y": k.Y}
+	case "OKP":
+		members = map[string]string{"crv": k.Crv, "kty": k.Kty, "x": k.X}
+	default:
+		return "", fmt.Errorf("dpop: unsupported kty %q", k.Kty)
+	}
+
+	// encoding/json sorts map keys, which is exactly the canonical ordering
+	// RFC 7638 requires.
+	canonical, err := json.Marshal(members)
+	if err != nil {
+		return "", fmt.Errorf("dpop: marshaling canonical jwk: %w", err)
+	}
+
+	sum := sha256.Sum256(canonical)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
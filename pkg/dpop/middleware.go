@@ -0,0 +1,75 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dpop
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/exposure-notifications-verification-server/pkg/api"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireProof returns gin middleware that enforces proof-of-possession on
+// the certificate-issuance endpoint. tokenJKT extracts the cnf.jkt claim
+// from the already-authenticated verification token (empty if the token
+// carries no cnf claim), and bearerToken returns the raw token string. A
+// request whose token has no cnf claim is let through unchanged, so
+// deployments remain backwards compatible with clients that predate DPoP
+// support; once a realm's clients are known to send cnf, it should reject
+// tokens without one instead.
+func RequireProof(cache *ReplayCache, tokenJKT func(*gin.Context) string, bearerToken func(*gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		jkt := tokenJKT(c)
+		if jkt == "" {
+			c.Next()
+			return
+		}
+
+		proofJWT := c.GetHeader("DPoP")
+		if proofJWT == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, api.Error("missing DPoP proof"))
+			return
+		}
+
+		ath := TokenHash(bearerToken(c))
+		url := requestURL(c)
+		proof, err := Verify(cache, proofJWT, c.Request.Method, url, ath, time.Now().UTC())
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, api.Error("invalid DPoP proof: %v", err))
+			return
+		}
+		if proof.Thumbprint != jkt {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, api.Error("DPoP proof was not signed by the key this token is bound to"))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// requestURL reconstructs the absolute URL DPoP proofs are expected to
+// cover (the `htu` claim), honoring a TLS-terminating proxy's forwarded
+// scheme header.
+func requestURL(c *gin.Context) string {
+	scheme := "https"
+	if forwarded := c.GetHeader("X-Forwarded-Proto"); forwarded != "" {
+		scheme = forwarded
+	} else if c.Request.TLS == nil {
+		scheme = "http"
+	}
+	return scheme + "://" + c.Request.Host + c.Request.URL.Path
+}
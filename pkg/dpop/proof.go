@@ -0,0 +1,239 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dpop
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// maxProofAge bounds how stale a DPoP proof's iat claim may be before it is
+// rejected outright, independent of jti replay tracking.
+const maxProofAge = 2 * time.Minute
+
+type proofHeader struct {
+	Typ string `json:"typ"`
+	Alg string `json:"alg"`
+	JWK JWK    `json:"jwk"`
+}
+
+type proofClaims struct {
+	JTI string `json:"jti"`
+	HTM string `json:"htm"`
+	HTU string `json:"htu"`
+	IAT int64  `json:"iat"`
+	ATH string `json:"ath,omitempty"`
+}
+
+// Proof is a verified DPoP proof: the claims it carried and the thumbprint
+// of the key that signed it. Callers compare Thumbprint against the
+// verification token's cnf.jkt claim to confirm the caller holds the same
+// key the token was bound to.
+type Proof struct {
+	Thumbprint string
+	JTI        string
+	IssuedAt   time.Time
+}
+
+// Verify checks proofJWT's signature, freshness, method/URL binding, and
+// (via cache) replay, then returns the signer's JWK thumbprint. tokenHash,
+// when non-empty, is the base64url SHA-256 hash of the access token the
+// proof must also be bound to (the `ath` claim).
+func Verify(cache *ReplayCache, proofJWT, method, url, tokenHash string, now time.Time) (*Proof, error) {
+	parts := strings.Split(proofJWT, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("dpop: malformed proof JWT")
+	}
+
+	var header proofHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("dpop: parsing header: %w", err)
+	}
+	if header.Typ != "dpop+jwt" {
+		return nil, fmt.Errorf("dpop: unexpected typ %q", header.Typ)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("dpop: decoding signature: %w", err)
+	}
+	if err := verifySignature(header, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims proofClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("dpop: parsing claims: %w", err)
+	}
+
+	iat := time.Unix(claims.IAT, 0)
+	if age := now.Sub(iat); age > maxProofAge || age < -maxProofAge {
+		return nil, fmt.Errorf("dpop: proof iat %s outside the %s freshness window", iat, maxProofAge)
+	}
+	if claims.HTM != method {
+		return nil, fmt.Errorf("dpop: proof htm %q does not match %q", claims.HTM, method)
+	}
+	if claims.HTU != url {
+		return nil, fmt.Errorf("dpop: proof htu %q does not match %q", claims.HTU, url)
+	}
+	if tokenHash != "" && claims.ATH != tokenHash {
+		return nil, errors.New("dpop: proof ath does not match presented token")
+	}
+	if claims.JTI == "" {
+		return nil, errors.New("dpop: proof missing jti")
+	}
+	if cache.Seen(claims.JTI, now) {
+		return nil, fmt.Errorf("dpop: proof jti %q already used", claims.JTI)
+	}
+
+	thumbprint, err := header.JWK.Thumbprint()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Proof{Thumbprint: thumbprint, JTI: claims.JTI, IssuedAt: iat}, nil
+}
+
+// Issue builds and signs a DPoP proof JWT for method/url using priv, whose
+// public half is described by jwk. It exists mainly to exercise Verify in
+// tests; real proofs are produced by the mobile client.
+func Issue(priv crypto.Signer, jwk JWK, method, url, tokenHash string, now time.Time) (string, error) {
+	var alg string
+	switch priv.(type) {
+	case *ecdsa.PrivateKey:
+		alg = "ES256"
+	case ed25519.PrivateKey:
+		alg = "EdDSA"
+	default:
+		return "", fmt.Errorf("dpop: unsupported private key type %T", priv)
+	}
+
+	header := proofHeader{Typ: "dpop+jwt", Alg: alg, JWK: jwk}
+	claims := proofClaims{JTI: randomJTI(), HTM: method, HTU: url, IAT: now.Unix(), ATH: tokenHash}
+
+	headerSeg, err := encodeSegment(header)
+	if err != nil {
+		return "", err
+	}
+	claimsSeg, err := encodeSegment(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := headerSeg + "." + claimsSeg
+
+	var signature []byte
+	switch key := priv.(type) {
+	case *ecdsa.PrivateKey:
+		sum := sha256.Sum256([]byte(signingInput))
+		r, s, err := ecdsa.Sign(rand.Reader, key, sum[:])
+		if err != nil {
+			return "", fmt.Errorf("dpop: signing proof: %w", err)
+		}
+		size := (key.Curve.Params().BitSize + 7) / 8
+		signature = append(r.FillBytes(make([]byte, size)), s.FillBytes(make([]byte, size))...)
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(key, []byte(signingInput))
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func verifySignature(header proofHeader, signingInput string, signature []byte) error {
+	switch header.JWK.Kty {
+	case "EC":
+		if header.Alg != "ES256" {
+			return fmt.Errorf("dpop: alg %q does not match EC key", header.Alg)
+		}
+		pub, err := ecPublicKey(header.JWK)
+		if err != nil {
+			return err
+		}
+		if len(signature) != 64 {
+			return errors.New("dpop: malformed ES256 signature")
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		sum := sha256.Sum256([]byte(signingInput))
+		if !ecdsa.Verify(pub, sum[:], r, s) {
+			return errors.New("dpop: signature verification failed")
+		}
+		return nil
+
+	case "OKP":
+		if header.Alg != "EdDSA" {
+			return fmt.Errorf("dpop: alg %q does not match OKP key", header.Alg)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(header.JWK.X)
+		if err != nil {
+			return fmt.Errorf("dpop: decoding OKP x: %w", err)
+		}
+		if !ed25519.Verify(ed25519.PublicKey(x), []byte(signingInput), signature) {
+			return errors.New("dpop: signature verification failed")
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("dpop: unsupported kty %q", header.JWK.Kty)
+	}
+}
+
+func ecPublicKey(k JWK) (*ecdsa.PublicKey, error) {
+	if k.Crv != "P-256" {
+		return nil, fmt.Errorf("dpop: unsupported EC curve %q", k.Crv)
+	}
+	x, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil {
+		return nil, fmt.Errorf("dpop: decoding EC x: %w", err)
+	}
+	y, err := base64.RawURLEncoding.DecodeString(k.Y)
+	if err != nil {
+		return nil, fmt.Errorf("dpop: decoding EC y: %w", err)
+	}
+	return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+func encodeSegment(v interface{}) (string, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// randomJTI returns a random 16-byte identifier, base64url encoded.
+func randomJTI() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dpop
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func ecJWK(t *testing.T, priv *ecdsa.PrivateKey) JWK {
+	t.Helper()
+	size := (priv.Curve.Params().BitSize + 7) / 8
+	return JWK{
+		Kty: "EC",
+		Crv: priv.Curve.Params().Name,
+		X:   base64.RawURLEncoding.EncodeToString(priv.X.FillBytes(make([]byte, size))),
+		Y:   base64.RawURLEncoding.EncodeToString(priv.Y.FillBytes(make([]byte, size))),
+	}
+}
+
+func TestVerify_ECRoundTrip(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	jwk := ecJWK(t, priv)
+
+	now := time.Unix(1_700_000_000, 0)
+	proofJWT, err := Issue(priv, jwk, "POST", "https://verify.example.com/api/certificate", "", now)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	wantThumbprint, err := jwk.Thumbprint()
+	if err != nil {
+		t.Fatalf("Thumbprint: %v", err)
+	}
+
+	cache := NewReplayCache()
+	proof, err := Verify(cache, proofJWT, "POST", "https://verify.example.com/api/certificate", "", now)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if proof.Thumbprint != wantThumbprint {
+		t.Errorf("Thumbprint = %q, want %q", proof.Thumbprint, wantThumbprint)
+	}
+
+	// Replaying the same proof must fail even though nothing else changed.
+	if _, err := Verify(cache, proofJWT, "POST", "https://verify.example.com/api/certificate", "", now); err == nil {
+		t.Errorf("Verify (replay): expected error, got nil")
+	}
+}
+
+func TestVerify_EdDSARoundTrip(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	jwk := JWK{Kty: "OKP", Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(pub)}
+
+	now := time.Unix(1_700_000_000, 0)
+	proofJWT, err := Issue(priv, jwk, "POST", "https://verify.example.com/api/certificate", "token-hash", now)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	cache := NewReplayCache()
+	proof, err := Verify(cache, proofJWT, "POST", "https://verify.example.com/api/certificate", "token-hash", now)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	wantThumbprint, _ := jwk.Thumbprint()
+	if proof.Thumbprint != wantThumbprint {
+		t.Errorf("Thumbprint = %q, want %q", proof.Thumbprint, wantThumbprint)
+	}
+}
+
+func TestVerify_RejectsWrongURL(t *testing.T) {
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	jwk := ecJWK(t, priv)
+	now := time.Unix(1_700_000_000, 0)
+
+	proofJWT, err := Issue(priv, jwk, "POST", "https://verify.example.com/api/certificate", "", now)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	cache := NewReplayCache()
+	if _, err := Verify(cache, proofJWT, "POST", "https://attacker.example.com/api/certificate", "", now); err == nil {
+		t.Errorf("Verify: expected error for mismatched htu, got nil")
+	}
+}
+
+func TestVerify_RejectsStaleProof(t *testing.T) {
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	jwk := ecJWK(t, priv)
+	issuedAt := time.Unix(1_700_000_000, 0)
+
+	proofJWT, err := Issue(priv, jwk, "POST", "https://verify.example.com/api/certificate", "", issuedAt)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	cache := NewReplayCache()
+	later := issuedAt.Add(5 * time.Minute)
+	if _, err := Verify(cache, proofJWT, "POST", "https://verify.example.com/api/certificate", "", later); err == nil {
+		t.Errorf("Verify: expected error for stale proof, got nil")
+	}
+}
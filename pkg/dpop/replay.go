@@ -0,0 +1,64 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dpop
+
+import (
+	"sync"
+	"time"
+)
+
+// replayTTL is how long a DPoP proof's jti is remembered. It only needs to
+// exceed the proof's own freshness window (see maxProofAge in proof.go): once
+// a proof is too old to accept on its own merits, there's no need to keep
+// remembering its jti either.
+const replayTTL = 2 * time.Minute
+
+// ReplayCache remembers recently seen DPoP proof jtis so a captured proof
+// can't be replayed. It is a map bounded by TTL rather than count: entries
+// older than replayTTL are swept on every call to Seen. The number of
+// entries is not capped, and entries are not evicted by recency of use.
+type ReplayCache struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// NewReplayCache creates an empty ReplayCache.
+func NewReplayCache() *ReplayCache {
+	return &ReplayCache{seen: make(map[string]time.Time)}
+}
+
+// Seen records jti and reports whether it had already been seen within
+// replayTTL. Callers should reject the proof when it returns true.
+func (c *ReplayCache) Seen(jti string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sweep(now)
+
+	if seenAt, ok := c.seen[jti]; ok && now.Sub(seenAt) < replayTTL {
+		return true
+	}
+	c.seen[jti] = now
+	return false
+}
+
+// sweep drops entries older than replayTTL. Callers must hold c.mu.
+func (c *ReplayCache) sweep(now time.Time) {
+	for jti, seenAt := range c.seen {
+		if now.Sub(seenAt) >= replayTTL {
+			delete(c.seen, jti)
+		}
+	}
+}
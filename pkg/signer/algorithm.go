@@ -0,0 +1,70 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signer
+
+import (
+	"fmt"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Algorithm identifies the JWS signing algorithm a key was provisioned for,
+// using the same names as RFC 7518 / the `alg` JWT header.
+type Algorithm string
+
+const (
+	// AlgorithmES256 signs with an EC P-256 key (the server's long-standing
+	// default).
+	AlgorithmES256 Algorithm = "ES256"
+
+	// AlgorithmRS256 signs with an RSA key, for deployments whose KMS or HSM
+	// only exposes RSA key material.
+	AlgorithmRS256 Algorithm = "RS256"
+
+	// AlgorithmEdDSA signs with an Ed25519 key. The JWKS document publishes
+	// such a key, but JWTSigningMethod returns an error for it:
+	// github.com/dgrijalva/jwt-go (this server's JWT library) has no EdDSA
+	// support. An EdDSA key therefore cannot be a KeySet's primary, this
+	// server issues no EdDSA-signed tokens, and the discovery document does
+	// not list EdDSA among its signing algorithms.
+	AlgorithmEdDSA Algorithm = "EdDSA"
+)
+
+// ParseAlgorithm validates s against the supported Algorithm values. An
+// empty string defaults to AlgorithmES256 for backwards compatibility with
+// deployments that predate multi-algorithm support.
+func ParseAlgorithm(s string) (Algorithm, error) {
+	switch Algorithm(s) {
+	case "":
+		return AlgorithmES256, nil
+	case AlgorithmES256, AlgorithmRS256, AlgorithmEdDSA:
+		return Algorithm(s), nil
+	default:
+		return "", fmt.Errorf("signer: unsupported algorithm %q", s)
+	}
+}
+
+// JWTSigningMethod returns the jwt.SigningMethod Execute should sign with
+// for this algorithm.
+func (a Algorithm) JWTSigningMethod() (jwt.SigningMethod, error) {
+	switch a {
+	case AlgorithmES256:
+		return jwt.SigningMethodES256, nil
+	case AlgorithmRS256:
+		return jwt.SigningMethodRS256, nil
+	default:
+		return nil, fmt.Errorf("signer: %q is not signable by this deployment's JWT library", a)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signer
+
+import "testing"
+
+func TestParseAlgorithm(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    Algorithm
+		wantErr bool
+	}{
+		{"", AlgorithmES256, false},
+		{"ES256", AlgorithmES256, false},
+		{"RS256", AlgorithmRS256, false},
+		{"EdDSA", AlgorithmEdDSA, false},
+		{"HS256", "", true},
+	}
+	for _, tc := range cases {
+		got, err := ParseAlgorithm(tc.in)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ParseAlgorithm(%q) error = %v, wantErr %v", tc.in, err, tc.wantErr)
+			continue
+		}
+		if err == nil && got != tc.want {
+			t.Errorf("ParseAlgorithm(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestAlgorithm_JWTSigningMethod(t *testing.T) {
+	if _, err := AlgorithmES256.JWTSigningMethod(); err != nil {
+		t.Errorf("AlgorithmES256.JWTSigningMethod(): %v", err)
+	}
+	if _, err := AlgorithmRS256.JWTSigningMethod(); err != nil {
+		t.Errorf("AlgorithmRS256.JWTSigningMethod(): %v", err)
+	}
+	if _, err := AlgorithmEdDSA.JWTSigningMethod(); err == nil {
+		t.Errorf("AlgorithmEdDSA.JWTSigningMethod(): expected error, got nil")
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signer
+
+import "fmt"
+
+// KeyConfig is the config-loader representation of a single entry in a
+// rotatable signing key set. A deployment's full list of KeyConfig values
+// (config.Config.SigningKeys) is turned into a KeySet at startup via
+// KeySetFromConfig.
+type KeyConfig struct {
+	// KeyID is passed to KeyManager.NewSigner to obtain a signer for this
+	// key, e.g. a local file path or a KMS CryptoKeyVersion resource name.
+	KeyID string `env:"KEY_ID"`
+
+	// KID is the stable identifier published in the `kid` JWT header and the
+	// JWKS document. Defaults to KeyID when empty.
+	KID string `env:"KID"`
+
+	// Alg is the signing algorithm this key was provisioned for: ES256,
+	// RS256, or EdDSA. Defaults to ES256 when empty.
+	Alg string `env:"ALG"`
+
+	// Primary marks the key used to sign newly issued tokens. Exactly one
+	// KeyConfig in a list must set Primary.
+	Primary bool `env:"PRIMARY"`
+}
+
+// KeySetFromConfig builds a KeySet from a list of KeyConfig, as loaded from
+// a deployment's configuration. Exactly one entry must have Primary set.
+func KeySetFromConfig(manager KeyManager, configs []KeyConfig) (*KeySet, error) {
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("signer: at least one signing key must be configured")
+	}
+
+	var primary *Key
+	var additional []Key
+	for _, c := range configs {
+		kid := c.KID
+		if kid == "" {
+			kid = c.KeyID
+		}
+		alg, err := ParseAlgorithm(c.Alg)
+		if err != nil {
+			return nil, err
+		}
+		key := Key{KeyID: c.KeyID, KID: kid, Algorithm: alg}
+
+		if c.Primary {
+			if primary != nil {
+				return nil, fmt.Errorf("signer: multiple signing keys marked primary (%q and %q)", primary.KID, key.KID)
+			}
+			k := key
+			primary = &k
+			continue
+		}
+		additional = append(additional, key)
+	}
+	if primary == nil {
+		return nil, fmt.Errorf("signer: no signing key marked primary")
+	}
+
+	return NewKeySet(manager, *primary, additional...)
+}
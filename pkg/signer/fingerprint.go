@@ -0,0 +1,59 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signer
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+)
+
+// KeyFingerprint identifies a key in operator-facing output, e.g. the
+// `listkeys` admin command used when rotating keys.
+type KeyFingerprint struct {
+	KID         string
+	KeyID       string
+	Primary     bool
+	Fingerprint string
+}
+
+// Fingerprints resolves every key in the set and returns its SHA-256
+// fingerprint, so an operator can confirm which physical key a kid
+// corresponds to before removing it from the configuration.
+func (ks *KeySet) Fingerprints(ctx context.Context) ([]KeyFingerprint, error) {
+	out := make([]KeyFingerprint, 0, len(ks.all))
+	for _, k := range ks.all {
+		s, err := ks.manager.NewSigner(ctx, k.KeyID)
+		if err != nil {
+			return nil, fmt.Errorf("signer: resolving %q: %w", k.KID, err)
+		}
+
+		der, err := x509.MarshalPKIXPublicKey(s.Public())
+		if err != nil {
+			return nil, fmt.Errorf("signer: marshaling public key for %q: %w", k.KID, err)
+		}
+		sum := sha256.Sum256(der)
+
+		out = append(out, KeyFingerprint{
+			KID:         k.KID,
+			KeyID:       k.KeyID,
+			Primary:     k.KID == ks.primary.KID,
+			Fingerprint: hex.EncodeToString(sum[:]),
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signer
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+)
+
+// Key describes one entry in a KeySet.
+type Key struct {
+	// KeyID is the resource name passed to KeyManager.NewSigner to obtain a
+	// signer for this key.
+	KeyID string
+
+	// KID is the stable identifier published in the JWT `kid` header and in
+	// the JWKS document. It must be unique within a KeySet and, unlike
+	// KeyID, should not change if the underlying key material is rotated
+	// under a KMS alias.
+	KID string
+
+	// Algorithm is the signing algorithm this key was provisioned for.
+	Algorithm Algorithm
+}
+
+// KeySet is an ordered collection of signing keys: exactly one primary, used
+// to sign newly issued tokens, plus zero or more retiring/standby keys that
+// remain resolvable by kid for verification and JWKS publication.
+//
+// This allows zero-downtime rotation: add the new key to the set, flip
+// Primary to it, wait out the token validity window so no previously issued
+// token still names the old kid, then remove the old key from the set.
+type KeySet struct {
+	manager KeyManager
+	primary Key
+	all     []Key
+}
+
+// NewKeySet builds a KeySet from primary plus any number of additional keys.
+// Every KID must be unique, keys must have a non-empty KID and KeyID, and
+// primary's algorithm must actually be signable by this deployment's JWT
+// library (see Algorithm.JWTSigningMethod) - a key that's only fit for
+// verification/JWKS publication cannot be the primary, since that's a
+// boot-time misconfiguration, not something Execute should discover by
+// failing every request.
+func NewKeySet(manager KeyManager, primary Key, additional ...Key) (*KeySet, error) {
+	all := append([]Key{primary}, additional...)
+	seen := make(map[string]bool, len(all))
+	for _, k := range all {
+		if k.KeyID == "" || k.KID == "" {
+			return nil, fmt.Errorf("signer: key %+v must have both KeyID and KID set", k)
+		}
+		if seen[k.KID] {
+			return nil, fmt.Errorf("signer: duplicate kid %q in key set", k.KID)
+		}
+		seen[k.KID] = true
+	}
+	if _, err := primary.Algorithm.JWTSigningMethod(); err != nil {
+		return nil, fmt.Errorf("signer: primary key %q cannot be used to sign: %w", primary.KID, err)
+	}
+
+	return &KeySet{manager: manager, primary: primary, all: all}, nil
+}
+
+// Primary returns the key used to sign newly issued tokens.
+func (ks *KeySet) Primary() Key {
+	return ks.primary
+}
+
+// Keys returns every key in the set: the primary first, then the additional
+// keys in the order they were given. Callers must not mutate the returned
+// slice.
+func (ks *KeySet) Keys() []Key {
+	return ks.all
+}
+
+// Lookup returns the Key with the given kid, if present.
+func (ks *KeySet) Lookup(kid string) (Key, bool) {
+	for _, k := range ks.all {
+		if k.KID == kid {
+			return k, true
+		}
+	}
+	return Key{}, false
+}
+
+// PrimarySigner returns a crypto.Signer for the primary key.
+func (ks *KeySet) PrimarySigner(ctx context.Context) (crypto.Signer, error) {
+	return ks.manager.NewSigner(ctx, ks.primary.KeyID)
+}
+
+// Signer returns a crypto.Signer for the key with the given kid, for
+// verification of tokens issued under a since-rotated-out key.
+func (ks *KeySet) Signer(ctx context.Context, kid string) (crypto.Signer, error) {
+	key, ok := ks.Lookup(kid)
+	if !ok {
+		return nil, fmt.Errorf("signer: unknown kid %q", kid)
+	}
+	return ks.manager.NewSigner(ctx, key.KeyID)
+}
@@ -0,0 +1,154 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signer
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+	"testing"
+)
+
+// fakeManager is an in-memory KeyManager backed by freshly generated ECDSA
+// keys, keyed by KeyID.
+type fakeManager struct {
+	keys map[string]*ecdsa.PrivateKey
+}
+
+func newFakeManager(keyIDs ...string) (*fakeManager, error) {
+	m := &fakeManager{keys: map[string]*ecdsa.PrivateKey{}}
+	for _, id := range keyIDs {
+		k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		m.keys[id] = k
+	}
+	return m, nil
+}
+
+func (m *fakeManager) NewSigner(ctx context.Context, keyID string) (crypto.Signer, error) {
+	k, ok := m.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("no such key %q", keyID)
+	}
+	return k, nil
+}
+
+func TestKeySetFromConfig_RotationWindow(t *testing.T) {
+	manager, err := newFakeManager("key-a", "key-b")
+	if err != nil {
+		t.Fatalf("newFakeManager: %v", err)
+	}
+
+	// Before rotation: key-a is primary, key-b isn't configured yet.
+	before, err := KeySetFromConfig(manager, []KeyConfig{
+		{KeyID: "key-a", KID: "a", Primary: true},
+	})
+	if err != nil {
+		t.Fatalf("KeySetFromConfig(before): %v", err)
+	}
+	if got := before.Primary().KID; got != "a" {
+		t.Errorf("before.Primary().KID = %q, want %q", got, "a")
+	}
+
+	// During rotation: both keys are configured, key-b is now primary, but
+	// tokens signed under key-a (still in flight) must remain verifiable.
+	during, err := KeySetFromConfig(manager, []KeyConfig{
+		{KeyID: "key-a", KID: "a"},
+		{KeyID: "key-b", KID: "b", Primary: true},
+	})
+	if err != nil {
+		t.Fatalf("KeySetFromConfig(during): %v", err)
+	}
+	if got := during.Primary().KID; got != "b" {
+		t.Errorf("during.Primary().KID = %q, want %q", got, "b")
+	}
+
+	primarySigner, err := during.PrimarySigner(context.Background())
+	if err != nil {
+		t.Fatalf("PrimarySigner: %v", err)
+	}
+	if !primarySigner.Public().(*ecdsa.PublicKey).Equal(manager.keys["key-b"].Public()) {
+		t.Errorf("PrimarySigner did not resolve to key-b")
+	}
+
+	oldSigner, err := during.Signer(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("Signer(a) during rotation: %v", err)
+	}
+	if !oldSigner.Public().(*ecdsa.PublicKey).Equal(manager.keys["key-a"].Public()) {
+		t.Errorf("Signer(a) did not resolve to key-a")
+	}
+
+	// After the grace period: key-a is removed entirely and must no longer
+	// resolve.
+	after, err := KeySetFromConfig(manager, []KeyConfig{
+		{KeyID: "key-b", KID: "b", Primary: true},
+	})
+	if err != nil {
+		t.Fatalf("KeySetFromConfig(after): %v", err)
+	}
+	if _, err := after.Signer(context.Background(), "a"); err == nil {
+		t.Errorf("Signer(a) after removal: expected error, got nil")
+	}
+}
+
+func TestKeySetFromConfig_Validation(t *testing.T) {
+	manager, err := newFakeManager("key-a")
+	if err != nil {
+		t.Fatalf("newFakeManager: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		configs []KeyConfig
+	}{
+		{"empty", nil},
+		{"no primary", []KeyConfig{{KeyID: "key-a", KID: "a"}}},
+		{"two primaries", []KeyConfig{
+			{KeyID: "key-a", KID: "a", Primary: true},
+			{KeyID: "key-a", KID: "a2", Primary: true},
+		}},
+		{"primary not signable", []KeyConfig{
+			{KeyID: "key-a", KID: "a", Alg: string(AlgorithmEdDSA), Primary: true},
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := KeySetFromConfig(manager, tc.configs); err == nil {
+				t.Errorf("KeySetFromConfig(%v): expected error, got nil", tc.configs)
+			}
+		})
+	}
+}
+
+func TestKeySetFromConfig_NonPrimaryEdDSAIsFine(t *testing.T) {
+	// EdDSA keys are valid for verification/JWKS publication; the
+	// restriction is only on being primary (see JWTSigningMethod).
+	manager, err := newFakeManager("key-a", "key-b")
+	if err != nil {
+		t.Fatalf("newFakeManager: %v", err)
+	}
+	if _, err := KeySetFromConfig(manager, []KeyConfig{
+		{KeyID: "key-a", KID: "a", Primary: true},
+		{KeyID: "key-b", KID: "b", Alg: string(AlgorithmEdDSA)},
+	}); err != nil {
+		t.Errorf("KeySetFromConfig with non-primary EdDSA key: %v", err)
+	}
+}
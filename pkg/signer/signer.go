@@ -0,0 +1,32 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package signer abstracts over the key management system used to sign
+// verification tokens (a local file, Google Cloud KMS, AWS KMS, HashiCorp
+// Vault, etc) and manages the set of keys a deployment has active at once.
+package signer
+
+import (
+	"context"
+	"crypto"
+)
+
+// KeyManager resolves a named key to a crypto.Signer capable of signing with
+// it. Implementations are expected to cache or lazily dial whatever backend
+// they wrap.
+type KeyManager interface {
+	// NewSigner returns a crypto.Signer for the given key identifier (e.g. a
+	// file path or a KMS CryptoKeyVersion resource name).
+	NewSigner(ctx context.Context, keyID string) (crypto.Signer, error)
+}
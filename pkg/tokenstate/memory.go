@@ -0,0 +1,84 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenstate
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-memory Store, used in tests and by deployments that
+// don't need introspection state to survive a restart.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]Record)}
+}
+
+func (s *MemoryStore) Insert(ctx context.Context, r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[r.TokenID] = r
+	return nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, tokenID string) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.records[tokenID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &r, nil
+}
+
+func (s *MemoryStore) MarkUsed(ctx context.Context, tokenID string) error {
+	return s.transition(tokenID, Used)
+}
+
+func (s *MemoryStore) Revoke(ctx context.Context, tokenID string) error {
+	return s.transition(tokenID, Revoked)
+}
+
+func (s *MemoryStore) transition(tokenID string, state State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.records[tokenID]
+	if !ok {
+		return ErrNotFound
+	}
+	r.State = state
+	s.records[tokenID] = r
+	return nil
+}
+
+// GC removes every record past expiry, regardless of State: an Active
+// record whose ExpiresAt has passed is simply a token nobody ever redeemed
+// or revoked, and it's just as safe to forget as a Used or Revoked one,
+// since Record.Active already treats it as inactive.
+func (s *MemoryStore) GC(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, r := range s.records {
+		if now.After(r.ExpiresAt) {
+			delete(s.records, id)
+		}
+	}
+}
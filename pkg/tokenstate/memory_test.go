@@ -0,0 +1,83 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenstate
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_InsertGetRevoke(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Unix(1_700_000_000, 0)
+
+	rec := Record{TokenID: "jti-1", TestType: "confirmed", TestDate: "2020-10-01", ExpiresAt: now.Add(time.Hour), State: Active}
+	if err := store.Insert(ctx, rec); err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+
+	got, err := store.Get(ctx, "jti-1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !got.Active(now) {
+		t.Errorf("Active(now) = false, want true")
+	}
+
+	if err := store.Revoke(ctx, "jti-1"); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	got, err = store.Get(ctx, "jti-1")
+	if err != nil {
+		t.Fatalf("Get after revoke: %v", err)
+	}
+	if got.Active(now) {
+		t.Errorf("Active(now) after revoke = true, want false")
+	}
+	if got.State != Revoked {
+		t.Errorf("State = %q, want %q", got.State, Revoked)
+	}
+}
+
+func TestMemoryStore_GetNotFound(t *testing.T) {
+	store := NewMemoryStore()
+	if _, err := store.Get(context.Background(), "missing"); err != ErrNotFound {
+		t.Errorf("Get(missing) err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestMemoryStore_GC(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Unix(1_700_000_000, 0)
+
+	_ = store.Insert(ctx, Record{TokenID: "active", ExpiresAt: now.Add(time.Hour), State: Active})
+	_ = store.Insert(ctx, Record{TokenID: "active-expired", ExpiresAt: now.Add(-time.Hour), State: Active})
+	_ = store.Insert(ctx, Record{TokenID: "used-expired", ExpiresAt: now.Add(-time.Hour), State: Used})
+
+	store.GC(now)
+
+	if _, err := store.Get(ctx, "active"); err != nil {
+		t.Errorf("Get(active) after GC: %v", err)
+	}
+	if _, err := store.Get(ctx, "active-expired"); err != ErrNotFound {
+		t.Errorf("Get(active-expired) after GC err = %v, want ErrNotFound", err)
+	}
+	if _, err := store.Get(ctx, "used-expired"); err != ErrNotFound {
+		t.Errorf("Get(used-expired) after GC err = %v, want ErrNotFound", err)
+	}
+}
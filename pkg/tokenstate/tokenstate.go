@@ -0,0 +1,85 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tokenstate tracks the lifecycle of issued verification tokens
+// (by JTI) so that an operator, or the key server, can ask whether a token
+// is still good before it's exchanged for a certificate. Signed JWTs are
+// otherwise stateless: once issued, nothing short of waiting out their
+// expiry stops a stolen one from being redeemed. This package is what lets
+// Store.Revoke actually take effect early.
+package tokenstate
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// State is the lifecycle state of an issued verification token.
+type State string
+
+const (
+	// Active tokens have been issued and neither used nor revoked.
+	Active State = "active"
+	// Revoked tokens were explicitly invalidated by an operator or the
+	// owning user before being redeemed.
+	Revoked State = "revoked"
+	// Used tokens have already been exchanged for a certificate and cannot
+	// be redeemed again.
+	Used State = "used"
+)
+
+// ErrNotFound is returned by Store.Get when no record exists for a JTI.
+var ErrNotFound = errors.New("tokenstate: not found")
+
+// Record is the persisted state of one issued verification token, keyed by
+// its JTI (the JWT `jti` claim, database.VerificationToken.TokenID).
+type Record struct {
+	TokenID   string
+	TestType  string
+	TestDate  string
+	ExpiresAt time.Time
+	State     State
+}
+
+// Active reports whether the record still represents a redeemable token as
+// of now.
+func (r Record) Active(now time.Time) bool {
+	return r.State == Active && now.Before(r.ExpiresAt)
+}
+
+// Store persists Records. MemoryStore is the in-process implementation
+// used in tests and by deployments that don't need this state to survive
+// a restart; database.Database implements it against the TokenState
+// table (see database.TokenState), indexed on expiry so
+// database.Database.PurgeTokenStates can cheaply sweep elapsed rows. A
+// multi-replica deployment that needs revocation to take effect on every
+// instance, and to survive a rolling restart, must use the latter.
+type Store interface {
+	// Insert records a newly issued token. Called once, right after
+	// VerifyCodeAndIssueToken.
+	Insert(ctx context.Context, r Record) error
+
+	// Get returns the record for tokenID, or ErrNotFound.
+	Get(ctx context.Context, tokenID string) (*Record, error)
+
+	// MarkUsed transitions tokenID to Used. Called when a token is redeemed
+	// for a certificate at the key server, which lives outside this tree -
+	// nothing here calls it yet, so until that call site exists a token
+	// this package issues stays Active until it expires or is revoked.
+	MarkUsed(ctx context.Context, tokenID string) error
+
+	// Revoke transitions tokenID to Revoked.
+	Revoke(ctx context.Context, tokenID string) error
+}